@@ -0,0 +1,226 @@
+// Package pullauth materializes Kubernetes dockerconfigjson pull secrets for private-registry
+// images referenced by a devfile's container components, sourced from the user's local docker
+// config, so that a component's Deployment can pull them without manual secret plumbing.
+package pullauth
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+
+	"github.com/openshift/odo/pkg/kclient"
+)
+
+// dockerConfigJSON mirrors the subset of ~/.docker/config.json odo needs.
+type dockerConfigJSON struct {
+	Auths map[string]dockerConfigEntry `json:"auths"`
+}
+
+type dockerConfigEntry struct {
+	Auth     string `json:"auth,omitempty"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// secretNamePrefix is the prefix used for pull secrets odo materializes on behalf of a component.
+const secretNamePrefix = "odo-pullsecret"
+
+// loadDockerConfig reads the user's docker config, honouring the $DOCKER_CONFIG override used by
+// the docker and podman CLIs.
+func loadDockerConfig() (*dockerConfigJSON, error) {
+	dir := os.Getenv("DOCKER_CONFIG")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to determine home directory")
+		}
+		dir = filepath.Join(home, ".docker")
+	}
+
+	path := filepath.Join(dir, "config.json")
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &dockerConfigJSON{}, nil
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "unable to read docker config %s", path)
+	}
+
+	var cfg dockerConfigJSON
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse docker config %s", path)
+	}
+	return &cfg, nil
+}
+
+// registryOf returns the registry hostname for a container image reference, defaulting to
+// Docker Hub's registry when the image has no explicit host, matching how the CRI resolves it.
+func registryOf(image string) string {
+	ref := strings.SplitN(image, "/", 2)
+	if len(ref) == 1 {
+		return "docker.io"
+	}
+	if !strings.ContainsAny(ref[0], ".:") && ref[0] != "localhost" {
+		return "docker.io"
+	}
+	return ref[0]
+}
+
+// registriesForContainers returns the distinct, non-Docker-Hub registries referenced by
+// containers' images, in a deterministic order. Docker Hub is excluded because odo has no way to
+// distinguish a public Docker Hub image from a private one, and materializing a pull secret for
+// every Docker Hub auth entry a user happens to have configured locally would be surprising.
+func registriesForContainers(containers []corev1.Container) []string {
+	seen := map[string]bool{}
+	var registries []string
+	for _, c := range containers {
+		registry := registryOf(c.Image)
+		if registry == "docker.io" || seen[registry] {
+			continue
+		}
+		seen[registry] = true
+		registries = append(registries, registry)
+	}
+	return registries
+}
+
+// secretName derives a deterministic, per-registry secret name for a component, so repeated
+// pushes reuse the same Secret instead of accumulating duplicates.
+func secretName(componentName, registry string) string {
+	h := sha256.Sum256([]byte(registry))
+	return fmt.Sprintf("%s-%s-%x", secretNamePrefix, componentName, h[:4])
+}
+
+// pendingSecret is a dockerconfigjson Secret this Plan will create once an owner reference is
+// available.
+type pendingSecret struct {
+	name string
+	data []byte
+}
+
+// Plan is the set of pull secrets a component's Deployment needs. Refs is ready to assign to
+// DeploymentParams/ImagePullSecrets as soon as the plan is computed; Materialize must be called
+// once the Deployment exists so the secrets can be owner-referenced to it.
+type Plan struct {
+	// Refs is the ImagePullSecrets list to set on the component's pod spec.
+	Refs []corev1.LocalObjectReference
+
+	pending []pendingSecret
+}
+
+// PlanPullSecrets inspects containers' images against the user's docker config and returns a
+// Plan describing the Secret(s) the component's Deployment needs to pull them. If explicitSecret
+// is non-empty (the --registry-secret flag), the plan references it verbatim and skips
+// credential discovery entirely.
+func PlanPullSecrets(containers []corev1.Container, componentName string, explicitSecret string) (Plan, error) {
+	if explicitSecret != "" {
+		return Plan{Refs: []corev1.LocalObjectReference{{Name: explicitSecret}}}, nil
+	}
+
+	registries := registriesForContainers(containers)
+	if len(registries) == 0 {
+		return Plan{}, nil
+	}
+
+	cfg, err := loadDockerConfig()
+	if err != nil {
+		return Plan{}, err
+	}
+
+	var plan Plan
+	for _, registry := range registries {
+		entry, ok := cfg.Auths[registry]
+		if !ok {
+			continue
+		}
+
+		name := secretName(componentName, registry)
+		dockerCfg, err := json.Marshal(dockerConfigJSON{Auths: map[string]dockerConfigEntry{registry: entry}})
+		if err != nil {
+			return Plan{}, errors.Wrapf(err, "unable to marshal pull secret for registry %s", registry)
+		}
+
+		plan.Refs = append(plan.Refs, corev1.LocalObjectReference{Name: name})
+		plan.pending = append(plan.pending, pendingSecret{name: name, data: dockerCfg})
+	}
+
+	return plan, nil
+}
+
+// Materialize creates or updates the plan's pull secrets in the cluster, owned by ownerRef
+// (typically the component's Deployment). It is idempotent: existing secrets are updated in
+// place rather than duplicated.
+func (p Plan) Materialize(client kclient.Client, ownerRef metav1.OwnerReference) error {
+	for _, ps := range p.pending {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            ps.name,
+				Namespace:       client.Namespace,
+				OwnerReferences: []metav1.OwnerReference{ownerRef},
+			},
+			Type: corev1.SecretTypeDockerConfigJson,
+			Data: map[string][]byte{
+				corev1.DockerConfigJsonKey: ps.data,
+			},
+		}
+
+		if err := createOrUpdatePullSecret(client, secret); err != nil {
+			return errors.Wrapf(err, "unable to create pull secret %s", ps.name)
+		}
+		klog.V(3).Infof("materialized pull secret %s", ps.name)
+	}
+	return nil
+}
+
+// createOrUpdatePullSecret creates secret, or updates it in place (preserving its
+// resourceVersion) if it already exists, so repeated pushes are idempotent.
+func createOrUpdatePullSecret(client kclient.Client, secret *corev1.Secret) error {
+	secrets := client.KubeClient.CoreV1().Secrets(client.Namespace)
+
+	existing, err := secrets.Get(secret.Name, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		_, err = secrets.Create(secret)
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	secret.ResourceVersion = existing.GetResourceVersion()
+	_, err = secrets.Update(secret)
+	return err
+}
+
+// CleanupOrphanedPullSecrets deletes any pull secrets odo previously materialized for
+// componentName that are still present with no owner references left. In the normal delete path
+// the Deployment owner reference is still on the secret when this runs, and Kubernetes' GC
+// removes the secret asynchronously afterwards; this sweep only catches secrets that were left
+// orphaned by something other than that GC, e.g. out-of-band removal of the owner reference
+// itself. It is best-effort and idempotent: a secret that's already gone is not an error.
+func CleanupOrphanedPullSecrets(client kclient.Client, componentName string) error {
+	secrets := client.KubeClient.CoreV1().Secrets(client.Namespace)
+	list, err := secrets.List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	prefix := fmt.Sprintf("%s-%s-", secretNamePrefix, componentName)
+	for _, secret := range list.Items {
+		if !strings.HasPrefix(secret.Name, prefix) || len(secret.OwnerReferences) > 0 {
+			continue
+		}
+		err = secrets.Delete(secret.Name, &metav1.DeleteOptions{})
+		if err != nil && !kerrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
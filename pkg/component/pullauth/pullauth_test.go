@@ -0,0 +1,49 @@
+package pullauth
+
+import "testing"
+
+func TestRegistryOf(t *testing.T) {
+	tests := []struct {
+		name  string
+		image string
+		want  string
+	}{
+		{name: "bare image defaults to docker hub", image: "nginx", want: "docker.io"},
+		{name: "docker hub namespaced image", image: "library/nginx", want: "docker.io"},
+		{name: "host with port", image: "registry.example.com:5000/team/app", want: "registry.example.com:5000"},
+		{name: "host with dot", image: "quay.io/org/app", want: "quay.io"},
+		{name: "localhost", image: "localhost/app", want: "localhost"},
+		{name: "localhost with port", image: "localhost:5000/app", want: "localhost:5000"},
+		{name: "short bare hostname without dot or port is not treated as a registry", image: "ghcr/app", want: "docker.io"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := registryOf(tt.image); got != tt.want {
+				t.Errorf("registryOf(%q) = %q, want %q", tt.image, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSecretName(t *testing.T) {
+	name1 := secretName("my-component", "ghcr.io")
+	name2 := secretName("my-component", "ghcr.io")
+	if name1 != name2 {
+		t.Errorf("secretName is not deterministic: %q != %q", name1, name2)
+	}
+
+	other := secretName("my-component", "quay.io")
+	if name1 == other {
+		t.Errorf("secretName(%q, ghcr.io) == secretName(%q, quay.io), want distinct names per registry", "my-component", "my-component")
+	}
+
+	otherComponent := secretName("other-component", "ghcr.io")
+	if name1 == otherComponent {
+		t.Errorf("secretName collides across components for the same registry: %q", name1)
+	}
+
+	if got := secretName("my-component", "ghcr.io"); len(got) == 0 {
+		t.Errorf("secretName returned an empty string")
+	}
+}
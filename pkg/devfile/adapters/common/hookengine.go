@@ -0,0 +1,238 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"time"
+
+	devfilev1 "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/library/pkg/devfile/parser/data"
+	parsercommon "github.com/devfile/library/pkg/devfile/parser/data/v2/common"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientgoexec "k8s.io/client-go/util/exec"
+	"k8s.io/klog"
+
+	"github.com/openshift/odo/pkg/kclient"
+)
+
+// HookPhase identifies where in the push lifecycle a set of hooks runs, generalizing the
+// existing single-shot PostStart/PreStop devfile events.
+type HookPhase string
+
+const (
+	// PreRunPhase runs after the component's Kubernetes resources are reconciled but before the
+	// devfile build/run commands execute, e.g. to run database migrations.
+	PreRunPhase HookPhase = "preRun"
+	// PostRunPhase runs after the devfile run command has started, e.g. to run a smoke test.
+	PostRunPhase HookPhase = "postRun"
+)
+
+// preRunAttribute and postRunAttribute are the odo-specific devfile command attributes used to
+// opt a command into the preRun/postRun hook phases.
+//
+// NOTE: this is a different configuration surface than an events.preRun/events.postRun list might
+// suggest. The devfile schema's events section only defines postStart/preStop (command-binding
+// event lists run once, at a fixed lifecycle point); it has no preRun/postRun event kind to hook
+// into. Rather than invent a new top-level events.* list, preRun/postRun hooks are opted into per
+// command via these attributes, e.g.:
+//
+//	commands:
+//	  - exec:
+//	      id: migrate-db
+//	      commandLine: "./migrate.sh"
+//	    attributes:
+//	      odo.dev/preRun: "true"
+//
+// A user looking for events.preRun/events.postRun in their devfile.yaml won't find it; the
+// attribute on the command itself is the supported way to configure this.
+const (
+	preRunAttribute  = "odo.dev/preRun"
+	postRunAttribute = "odo.dev/postRun"
+)
+
+// WaitConditionType identifies a single readiness condition a hook can be gated on.
+type WaitConditionType string
+
+const (
+	// WaitPodReady is satisfied once the component's pod is Running.
+	WaitPodReady WaitConditionType = "PodReady"
+	// WaitDeploymentAvailable is satisfied once the Deployment has rolled out its latest spec.
+	WaitDeploymentAvailable WaitConditionType = "DeploymentAvailable"
+	// WaitServiceEndpointsReady is satisfied once the component's Service has at least one ready address.
+	WaitServiceEndpointsReady WaitConditionType = "ServiceEndpointsReady"
+	// WaitCustomExec is satisfied once an arbitrary command run in a container exits with ExpectedExit.
+	WaitCustomExec WaitConditionType = "CustomExec"
+)
+
+// WaitCondition gates a Hook until it is satisfied. Container, Cmd and ExpectedExit only apply
+// to WaitCustomExec.
+type WaitCondition struct {
+	Type         WaitConditionType
+	Container    string
+	Cmd          []string
+	ExpectedExit int
+}
+
+// Hook is a named, ordered list of devfile commands gated by WaitConditions.
+type Hook struct {
+	Name           string
+	Phase          HookPhase
+	Commands       []devfilev1.Command
+	WaitConditions []WaitCondition
+}
+
+// Execer runs a command inside a component's container; satisfied by an adapter's
+// ExecCMDInContainer method.
+type Execer interface {
+	ExecCMDInContainer(componentInfo ComponentInfo, cmd []string, stdout, stderr io.Writer, stdin io.Reader, tty bool) error
+}
+
+// HookEngine evaluates WaitConditions against the cluster and, once satisfied, hands a hook's
+// commands back to the caller to execute via the existing devfile command machinery.
+type HookEngine struct {
+	Client        kclient.Client
+	ComponentName string
+
+	// PollInterval controls how often WaitConditions are re-checked.
+	PollInterval time.Duration
+	// Deadline bounds how long Run waits for a hook's WaitConditions before giving up.
+	Deadline time.Duration
+}
+
+// NewHookEngine creates a HookEngine with the odo defaults for poll interval and deadline.
+func NewHookEngine(client kclient.Client, componentName string) *HookEngine {
+	return &HookEngine{
+		Client:        client,
+		ComponentName: componentName,
+		PollInterval:  2 * time.Second,
+		Deadline:      5 * time.Minute,
+	}
+}
+
+// HooksForPhase returns the devfile commands tagged for phase via the odo.dev/preRun or
+// odo.dev/postRun command attribute, in devfile-declaration order, wrapped as a single Hook.
+func HooksForPhase(devfileData data.DevfileData, phase HookPhase, waitConditions []WaitCondition) (Hook, error) {
+	attribute := preRunAttribute
+	if phase == PostRunPhase {
+		attribute = postRunAttribute
+	}
+
+	commands, err := devfileData.GetCommands(parsercommon.DevfileOptions{})
+	if err != nil {
+		return Hook{}, errors.Wrapf(err, "unable to get devfile commands for %s hook", phase)
+	}
+
+	var tagged []devfilev1.Command
+	for _, cmd := range commands {
+		if _, ok := cmd.Attributes[attribute]; ok {
+			tagged = append(tagged, cmd)
+		}
+	}
+
+	return Hook{
+		Name:           string(phase),
+		Phase:          phase,
+		Commands:       tagged,
+		WaitConditions: waitConditions,
+	}, nil
+}
+
+// Run blocks until hook's WaitConditions are all satisfied (bounded by e.Deadline), then calls
+// runCommands with hook.Commands. If hook has no commands, runCommands is not called.
+func (e *HookEngine) Run(ctx context.Context, hook Hook, execer Execer, runCommands func([]devfilev1.Command) error) error {
+	if len(hook.WaitConditions) == 0 && len(hook.Commands) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.Deadline)
+	defer cancel()
+
+	for _, cond := range hook.WaitConditions {
+		if err := e.waitFor(ctx, cond, execer); err != nil {
+			return errors.Wrapf(err, "hook %q", hook.Name)
+		}
+	}
+
+	if len(hook.Commands) == 0 {
+		return nil
+	}
+	return runCommands(hook.Commands)
+}
+
+func (e *HookEngine) waitFor(ctx context.Context, cond WaitCondition, execer Execer) error {
+	klog.V(3).Infof("waiting on condition %s for component %s", cond.Type, e.ComponentName)
+	return wait.PollImmediateUntil(e.PollInterval, func() (bool, error) {
+		return e.check(cond, execer)
+	}, ctx.Done())
+}
+
+func (e *HookEngine) check(cond WaitCondition, execer Execer) (bool, error) {
+	switch cond.Type {
+	case WaitPodReady:
+		pod, err := e.Client.GetPodUsingComponentName(e.ComponentName)
+		if err != nil {
+			return false, nil
+		}
+		return pod.Status.Phase == corev1.PodRunning, nil
+
+	case WaitDeploymentAvailable:
+		deployment, err := e.Client.KubeClient.AppsV1().Deployments(e.Client.Namespace).Get(e.ComponentName, metav1.GetOptions{})
+		if err != nil {
+			return false, nil
+		}
+		if deployment.Spec.Replicas == nil {
+			return false, nil
+		}
+		return deployment.Status.ObservedGeneration >= deployment.Generation &&
+			deployment.Status.UpdatedReplicas == *deployment.Spec.Replicas, nil
+
+	case WaitServiceEndpointsReady:
+		endpoints, err := e.Client.KubeClient.CoreV1().Endpoints(e.Client.Namespace).Get(e.ComponentName, metav1.GetOptions{})
+		if err != nil {
+			return false, nil
+		}
+		for _, subset := range endpoints.Subsets {
+			if len(subset.Addresses) > 0 {
+				return true, nil
+			}
+		}
+		return false, nil
+
+	case WaitCustomExec:
+		if execer == nil {
+			return false, fmt.Errorf("CustomExec wait condition requires an execer")
+		}
+		pod, err := e.Client.GetPodUsingComponentName(e.ComponentName)
+		if err != nil {
+			return false, nil
+		}
+		var stdout, stderr bytes.Buffer
+		execErr := execer.ExecCMDInContainer(
+			ComponentInfo{PodName: pod.Name, ContainerName: cond.Container},
+			cond.Cmd, &stdout, &stderr, nil, false,
+		)
+		return exitCodeMatches(execErr, cond.ExpectedExit), nil
+
+	default:
+		return false, fmt.Errorf("unknown wait condition type %q", cond.Type)
+	}
+}
+
+// exitCodeMatches reports whether execErr represents the expected exit code: nil means 0, and a
+// *clientgoexec.CodeExitError carries the actual non-zero code.
+func exitCodeMatches(execErr error, expected int) bool {
+	if execErr == nil {
+		return expected == 0
+	}
+	var codeErr clientgoexec.CodeExitError
+	if stderrors.As(execErr, &codeErr) {
+		return codeErr.Code == expected
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package common
+
+import (
+	"errors"
+	"testing"
+
+	clientgoexec "k8s.io/client-go/util/exec"
+)
+
+func TestExitCodeMatches(t *testing.T) {
+	tests := []struct {
+		name     string
+		execErr  error
+		expected int
+		want     bool
+	}{
+		{name: "nil error matches expected 0", execErr: nil, expected: 0, want: true},
+		{name: "nil error does not match non-zero expected", execErr: nil, expected: 1, want: false},
+		{
+			name:     "matching code exit error",
+			execErr:  clientgoexec.CodeExitError{Err: errors.New("exit 2"), Code: 2},
+			expected: 2,
+			want:     true,
+		},
+		{
+			name:     "mismatched code exit error",
+			execErr:  clientgoexec.CodeExitError{Err: errors.New("exit 2"), Code: 2},
+			expected: 3,
+			want:     false,
+		},
+		{
+			name:     "error that is not a CodeExitError does not match",
+			execErr:  errors.New("wrapping an error is not a CodeExitError"),
+			expected: 0,
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCodeMatches(tt.execErr, tt.expected); got != tt.want {
+				t.Errorf("exitCodeMatches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,9 +1,11 @@
 package component
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
 	"reflect"
 	"strings"
 	"time"
@@ -13,6 +15,7 @@ import (
 	"github.com/openshift/odo/pkg/envinfo"
 	"github.com/openshift/odo/pkg/util"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -24,11 +27,14 @@ import (
 	devfilev1 "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
 	parsercommon "github.com/devfile/library/pkg/devfile/parser/data/v2/common"
 	"github.com/openshift/odo/pkg/component"
+	"github.com/openshift/odo/pkg/component/pullauth"
 	"github.com/openshift/odo/pkg/config"
 	"github.com/openshift/odo/pkg/devfile/adapters/common"
 	"github.com/openshift/odo/pkg/devfile/adapters/kubernetes/storage"
 	"github.com/openshift/odo/pkg/devfile/adapters/kubernetes/utils"
 	"github.com/openshift/odo/pkg/kclient"
+	"github.com/openshift/odo/pkg/kclient/portforward"
+	"github.com/openshift/odo/pkg/kclient/retry"
 	"github.com/openshift/odo/pkg/log"
 	"github.com/openshift/odo/pkg/occlient"
 	odoutil "github.com/openshift/odo/pkg/odo/util"
@@ -40,6 +46,10 @@ import (
 
 const supervisorDStatusWaitTimeInterval = 1
 
+// podReadyTimeout bounds how long the pod readiness watcher waits for a component's pod to
+// become Ready before giving up.
+const podReadyTimeout = 5 * time.Minute
+
 // New instantiates a component adapter
 func New(adapterContext common.AdapterContext, client kclient.Client) Adapter {
 
@@ -53,18 +63,53 @@ func New(adapterContext common.AdapterContext, client kclient.Client) Adapter {
 // is true, then the pod is refreshed from the cluster regardless of its current local state
 func (a *Adapter) getPod(refresh bool) (*corev1.Pod, error) {
 	if refresh || a.pod == nil {
-		podSelector := fmt.Sprintf("component=%s", a.ComponentName)
-
-		// Wait for Pod to be in running state otherwise we can't sync data to it.
-		pod, err := a.Client.WaitAndGetPodWithEvents(podSelector, corev1.PodRunning, "Waiting for component to start")
+		pod, err := a.waitForPodReady()
 		if err != nil {
-			return nil, errors.Wrapf(err, "error while waiting for pod %s", podSelector)
+			return nil, errors.Wrapf(err, "error while waiting for pod for component %s", a.ComponentName)
 		}
 		a.pod = pod
 	}
 	return a.pod, nil
 }
 
+// waitForPodReady watches the component's pod via a kclient.PodReadinessWatcher, rendering every
+// readiness transition through pkg/log as it happens, and returns the pod as soon as it is
+// Running. It deliberately doesn't wait for the pod's Ready condition: a container's readiness
+// probe can itself depend on code a push is about to sync in, so gating on Ready here would
+// deadlock push until podReadyTimeout instead of syncing as soon as the pod comes up, matching
+// the PodRunning gate this watcher replaced.
+func (a *Adapter) waitForPodReady() (*corev1.Pod, error) {
+	watcher := kclient.NewPodReadinessWatcher(a.Client, a.ComponentName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), podReadyTimeout)
+	defer cancel()
+
+	events, err := watcher.Start(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s := log.Spinner("Waiting for component to start")
+	defer s.End(false)
+
+	for ev := range events {
+		switch ev.Type {
+		case kclient.PodEventRunning, kclient.PodEventReady:
+			s.End(true)
+			return ev.Pod, nil
+		case kclient.PodEventDeleted:
+			return nil, fmt.Errorf("pod for component %s was deleted while waiting for it to start", a.ComponentName)
+		default:
+			log.Infof("\nPod %s: %s", ev.Type, a.ComponentName)
+		}
+	}
+
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("timed out waiting for pod for component %s to start: %w", a.ComponentName, ctx.Err())
+	}
+	return nil, fmt.Errorf("pod watch for component %s closed before the pod started", a.ComponentName)
+}
+
 func (a *Adapter) ComponentInfo(command devfilev1.Command) (common.ComponentInfo, error) {
 	pod, err := a.getPod(false)
 	if err != nil {
@@ -101,6 +146,7 @@ type Adapter struct {
 	devfileRunCmd    string
 	devfileDebugCmd  string
 	devfileDebugPort int
+	registrySecret   string
 	pod              *corev1.Pod
 }
 
@@ -116,6 +162,7 @@ func (a Adapter) Push(parameters common.PushParameters) (err error) {
 	a.devfileRunCmd = parameters.DevfileRunCmd
 	a.devfileDebugCmd = parameters.DevfileDebugCmd
 	a.devfileDebugPort = parameters.DebugPort
+	a.registrySecret = parameters.RegistrySecret
 
 	podChanged := false
 	var podName string
@@ -172,7 +219,11 @@ func (a Adapter) Push(parameters common.PushParameters) (err error) {
 		return errors.Wrap(err, "unable to create or update component")
 	}
 
-	deployment, err := a.Client.WaitForDeploymentRollout(a.ComponentName)
+	var deployment *appsv1.Deployment
+	err = retry.Do("wait for deployment rollout", nil, func() (err error) {
+		deployment, err = a.Client.WaitForDeploymentRollout(a.ComponentName)
+		return err
+	})
 	if err != nil {
 		return errors.Wrap(err, "error while waiting for deployment rollout")
 	}
@@ -184,7 +235,11 @@ func (a Adapter) Push(parameters common.PushParameters) (err error) {
 	}
 
 	// list the latest state of the PVCs
-	pvcs, err := a.Client.ListPVCs(fmt.Sprintf("%v=%v", "component", a.ComponentName))
+	var pvcs []corev1.PersistentVolumeClaim
+	err = retry.Do("list PVCs", nil, func() (err error) {
+		pvcs, err = a.Client.ListPVCs(fmt.Sprintf("%v=%v", "component", a.ComponentName))
+		return err
+	})
 	if err != nil {
 		return err
 	}
@@ -194,7 +249,10 @@ func (a Adapter) Push(parameters common.PushParameters) (err error) {
 		if pvcs[i].OwnerReferences != nil || pvcs[i].DeletionTimestamp != nil {
 			continue
 		}
-		err = a.Client.UpdateStorageOwnerReference(&pvcs[i], generator.GetOwnerReference(deployment))
+		pvc := &pvcs[i]
+		err = retry.Do("update PVC owner reference", nil, func() error {
+			return a.Client.UpdateStorageOwnerReference(pvc, generator.GetOwnerReference(deployment))
+		})
 		if err != nil {
 			return err
 		}
@@ -248,6 +306,29 @@ func (a Adapter) Push(parameters common.PushParameters) (err error) {
 	}
 
 	if execRequired || parameters.RunModeChanged {
+		hookEngine := common.NewHookEngine(a.Client, a.ComponentName)
+		runHookCommands := func(cmds []devfilev1.Command) error {
+			for _, cmd := range cmds {
+				if err := a.ExecuteDevfileCommand(cmd, parameters.Show); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		preRunHook, err := common.HooksForPhase(a.Devfile.Data, common.PreRunPhase, []common.WaitCondition{{Type: common.WaitPodReady}})
+		if err != nil {
+			return err
+		}
+		if len(preRunHook.Commands) > 0 {
+			s := log.Spinner("Running preRun hooks")
+			if err = hookEngine.Run(context.Background(), preRunHook, a, runHookCommands); err != nil {
+				s.End(false)
+				return errors.Wrap(err, "failed to run preRun hooks")
+			}
+			s.End(true)
+		}
+
 		log.Infof("\nExecuting devfile commands for component %s", a.ComponentName)
 		err = a.ExecDevfile(pushDevfileCommands, componentExists, parameters)
 		if err != nil {
@@ -267,11 +348,63 @@ func (a Adapter) Push(parameters common.PushParameters) (err error) {
 		if err != nil {
 			return err
 		}
+
+		postRunHook, err := common.HooksForPhase(a.Devfile.Data, common.PostRunPhase, []common.WaitCondition{{Type: common.WaitDeploymentAvailable}})
+		if err != nil {
+			return err
+		}
+		if len(postRunHook.Commands) > 0 {
+			s := log.Spinner("Running postRun hooks")
+			if err = hookEngine.Run(context.Background(), postRunHook, a, runHookCommands); err != nil {
+				s.End(false)
+				return errors.Wrap(err, "failed to run postRun hooks")
+			}
+			s.End(true)
+		}
 	} else {
 		// no file was modified/added/deleted/renamed, thus return without syncing files
 		log.Success("No file changes detected, skipping build. Use the '-f' flag to force the build.")
 	}
 
+	if parameters.Forward {
+		return a.watchAndForward(pod, parameters)
+	}
+
+	return nil
+}
+
+// watchAndForward keeps a port-forward session open for pod's containerPorts, reattaching
+// automatically across redeploys via the pod readiness watcher, until the user interrupts odo
+// push with Ctrl-C. If parameters.ForwardKeepAlive is false, the component is also deleted on
+// the way out, mirroring Adapter.Delete.
+func (a Adapter) watchAndForward(pod *corev1.Pod, parameters common.PushParameters) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	watcher := kclient.NewPodReadinessWatcher(a.Client, a.ComponentName)
+	podEvents, err := watcher.Start(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to start pod watcher for port-forward")
+	}
+
+	forwarder := portforward.NewForwarder(a.Client, a.ComponentName)
+	if err := forwarder.WatchAndForward(ctx, pod.Name, pod.Spec.Containers, podEvents); err != nil {
+		return errors.Wrap(err, "unable to start port-forward")
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	log.Info("\nForwarding active, press Ctrl-C to stop")
+	<-interrupt
+
+	cancel()
+	forwarder.Stop()
+
+	if !parameters.ForwardKeepAlive {
+		return a.Delete(map[string]string{"component": a.ComponentName}, parameters.Show)
+	}
 	return nil
 }
 
@@ -433,7 +566,11 @@ func (a Adapter) createOrUpdateComponent(componentExists bool, ei envinfo.EnvSpe
 	volumeNameToPVCName := make(map[string]string)
 
 	// list all the pvcs for the component
-	pvcs, err := a.Client.ListPVCs(fmt.Sprintf("%v=%v", "component", a.ComponentName))
+	var pvcs []corev1.PersistentVolumeClaim
+	err = retry.Do("list PVCs", nil, func() (err error) {
+		pvcs, err = a.Client.ListPVCs(fmt.Sprintf("%v=%v", "component", a.ComponentName))
+		return err
+	})
 	if err != nil {
 		return err
 	}
@@ -469,6 +606,14 @@ func (a Adapter) createOrUpdateComponent(componentExists bool, ei envinfo.EnvSpe
 
 	deployment := generator.GetDeployment(deployParams)
 
+	// plan any private-registry pull secrets the containers' images need; the secrets themselves
+	// are materialized below, once the deployment (their owner) exists
+	pullSecretPlan, err := pullauth.PlanPullSecrets(containers, componentName, a.registrySecret)
+	if err != nil {
+		return err
+	}
+	deployment.Spec.Template.Spec.ImagePullSecrets = pullSecretPlan.Refs
+
 	serviceParams := generator.ServiceParams{
 		ObjectMeta:     objectMeta,
 		SelectorLabels: selectorLabels,
@@ -483,7 +628,25 @@ func (a Adapter) createOrUpdateComponent(componentExists bool, ei envinfo.EnvSpe
 	if componentExists {
 		// If the component already exists, get the resource version of the deploy before updating
 		klog.V(2).Info("The component already exists, attempting to update it")
-		deployment, err = a.Client.UpdateDeployment(*deployment)
+		// desired holds the spec we want applied; deployment is only reassigned once the update
+		// actually succeeds, so a failed attempt (which returns a nil object) never clobbers it
+		desired := deployment
+		err = retry.DoWithConflictRefresh("update deployment", nil, func() error {
+			updated, updateErr := a.Client.UpdateDeployment(*desired)
+			if updateErr != nil {
+				return updateErr
+			}
+			deployment = updated
+			return nil
+		}, func() error {
+			// on conflict, refresh the resourceVersion and re-apply our desired spec on top of it
+			latest, getErr := a.Client.KubeClient.AppsV1().Deployments(a.Client.Namespace).Get(componentName, metav1.GetOptions{})
+			if getErr != nil {
+				return getErr
+			}
+			desired.ResourceVersion = latest.GetResourceVersion()
+			return nil
+		})
 		if err != nil {
 			return err
 		}
@@ -491,10 +654,16 @@ func (a Adapter) createOrUpdateComponent(componentExists bool, ei envinfo.EnvSpe
 		oldSvc, err := a.Client.KubeClient.CoreV1().Services(a.Client.Namespace).Get(componentName, metav1.GetOptions{})
 		ownerReference := generator.GetOwnerReference(deployment)
 		service.OwnerReferences = append(service.OwnerReferences, ownerReference)
+		if err := pullSecretPlan.Materialize(a.Client, ownerReference); err != nil {
+			return err
+		}
 		if err != nil {
 			// no old service was found, create a new one
 			if len(service.Spec.Ports) > 0 {
-				_, err = a.Client.CreateService(*service)
+				err = retry.Do("create service", nil, func() (err error) {
+					_, err = a.Client.CreateService(*service)
+					return err
+				})
 				if err != nil {
 					return err
 				}
@@ -504,7 +673,17 @@ func (a Adapter) createOrUpdateComponent(componentExists bool, ei envinfo.EnvSpe
 			if len(service.Spec.Ports) > 0 {
 				service.Spec.ClusterIP = oldSvc.Spec.ClusterIP
 				service.ResourceVersion = oldSvc.GetResourceVersion()
-				_, err = a.Client.UpdateService(*service)
+				err = retry.DoWithConflictRefresh("update service", nil, func() (err error) {
+					_, err = a.Client.UpdateService(*service)
+					return err
+				}, func() error {
+					latest, getErr := a.Client.KubeClient.CoreV1().Services(a.Client.Namespace).Get(componentName, metav1.GetOptions{})
+					if getErr != nil {
+						return getErr
+					}
+					service.ResourceVersion = latest.GetResourceVersion()
+					return nil
+				})
 				if err != nil {
 					return err
 				}
@@ -517,15 +696,24 @@ func (a Adapter) createOrUpdateComponent(componentExists bool, ei envinfo.EnvSpe
 			}
 		}
 	} else {
-		deployment, err = a.Client.CreateDeployment(*deployment)
+		err = retry.Do("create deployment", nil, func() (err error) {
+			deployment, err = a.Client.CreateDeployment(*deployment)
+			return err
+		})
 		if err != nil {
 			return err
 		}
 		klog.V(2).Infof("Successfully created component %v", componentName)
 		ownerReference := generator.GetOwnerReference(deployment)
 		service.OwnerReferences = append(service.OwnerReferences, ownerReference)
+		if err := pullSecretPlan.Materialize(a.Client, ownerReference); err != nil {
+			return err
+		}
 		if len(service.Spec.Ports) > 0 {
-			_, err = a.Client.CreateService(*service)
+			err = retry.Do("create service", nil, func() (err error) {
+				_, err = a.Client.CreateService(*service)
+				return err
+			})
 			if err != nil {
 				return err
 			}
@@ -600,6 +788,16 @@ func (a Adapter) Delete(labels map[string]string, show bool) error {
 		return err
 	}
 
+	// Pull secrets are owner-referenced to the Deployment and are normally garbage-collected
+	// along with it by Kubernetes' asynchronous GC, not by this call: immediately after
+	// DeleteDeployment the secrets still carry the owner reference, so this sweep is a no-op on
+	// the normal path. It only catches secrets left behind by an out-of-band deletion that
+	// cleared the owner reference without the GC following through (e.g. the Deployment was
+	// force-deleted by something other than odo before the owner ref was resolved).
+	if err := pullauth.CleanupOrphanedPullSecrets(a.Client, a.ComponentName); err != nil {
+		klog.V(2).Infof("failed to clean up pull secrets for component %s: %v", a.ComponentName, err)
+	}
+
 	spinner.End(true)
 	log.Successf("Successfully deleted component")
 	return nil
@@ -0,0 +1,203 @@
+package kclient
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+)
+
+// PodEventType describes a readiness transition observed for a pod
+type PodEventType string
+
+const (
+	// PodEventPending means the pod has been scheduled but none of its containers are running yet
+	PodEventPending PodEventType = "Pending"
+	// PodEventContainerCreating means at least one container is still being created
+	PodEventContainerCreating PodEventType = "ContainerCreating"
+	// PodEventImagePullBackOff means a container is stuck pulling its image
+	PodEventImagePullBackOff PodEventType = "ImagePullBackOff"
+	// PodEventCrashLoopBackOff means a container has crashed and is being restarted by the kubelet
+	PodEventCrashLoopBackOff PodEventType = "CrashLoopBackOff"
+	// PodEventRunning means the pod's phase is Running, regardless of its Ready condition; this is
+	// the gate callers sync source code against, matching the pre-informer PodRunning gate, since
+	// a readiness probe can depend on the very code a push is about to sync in.
+	PodEventRunning PodEventType = "Running"
+	// PodEventReady means the pod's Ready condition is true
+	PodEventReady PodEventType = "Ready"
+	// PodEventDeleted means the pod has been removed from the cluster
+	PodEventDeleted PodEventType = "Deleted"
+)
+
+// PodEvent is a single, deduplicated readiness transition for a watched pod
+type PodEvent struct {
+	Type    PodEventType
+	Pod     *corev1.Pod
+	Message string
+}
+
+// PodReadinessWatcher watches the pod(s) for a single component via a namespace and
+// label-scoped SharedInformerFactory, instead of polling the API server for status.
+type PodReadinessWatcher struct {
+	componentName string
+	factory       informers.SharedInformerFactory
+	lastEvent     PodEventType
+
+	// mu guards lastEvent and closed, and serializes emit against the channel close triggered by
+	// ctx.Done(), so emit can never send on the channel after it's been closed.
+	mu     sync.Mutex
+	closed bool
+}
+
+// NewPodReadinessWatcher creates a watcher for the pod(s) belonging to componentName, scoped
+// to the client's namespace and the "component=<componentName>" label selector.
+func NewPodReadinessWatcher(client Client, componentName string) *PodReadinessWatcher {
+	selector := fmt.Sprintf("component=%s", componentName)
+	factory := informers.NewSharedInformerFactoryWithOptions(
+		client.KubeClient,
+		10*time.Minute,
+		informers.WithNamespace(client.Namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = selector
+		}),
+	)
+	return &PodReadinessWatcher{
+		componentName: componentName,
+		factory:       factory,
+	}
+}
+
+// Start begins watching and returns a channel of deduplicated PodEvents derived from the pod's
+// status.conditions and container statuses. The channel is closed when ctx is cancelled.
+func (w *PodReadinessWatcher) Start(ctx context.Context) (<-chan PodEvent, error) {
+	events := make(chan PodEvent, 10)
+
+	informer := w.factory.Core().V1().Pods().Informer()
+
+	handler := func(obj interface{}) {
+		pod, ok := obj.(*corev1.Pod)
+		if !ok {
+			return
+		}
+		if pod.DeletionTimestamp != nil {
+			// The label selector scoping this informer to "component=<name>" matches both the
+			// old and new pod during a rollout; ignore the old pod once it starts terminating so
+			// a Ready condition that hasn't yet caught up with the termination can't be mistaken
+			// for the new pod becoming ready.
+			return
+		}
+		w.emit(events, classifyPod(pod), pod, "")
+	}
+
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    handler,
+		UpdateFunc: func(oldObj, newObj interface{}) { handler(newObj) },
+		DeleteFunc: func(obj interface{}) {
+			pod, ok := obj.(*corev1.Pod)
+			if !ok {
+				if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+					pod, _ = tombstone.Obj.(*corev1.Pod)
+				}
+			}
+			w.emit(events, PodEventDeleted, pod, "pod deleted")
+		},
+	})
+
+	w.factory.Start(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+		close(events)
+		return nil, fmt.Errorf("failed to sync pod informer cache for component %s", w.componentName)
+	}
+
+	go func() {
+		<-ctx.Done()
+		// Close under the same lock emit uses, so an informer callback racing with shutdown
+		// either completes its send before the channel closes, or observes w.closed and skips
+		// the send entirely; without this, a send on the now-closed channel would panic.
+		w.mu.Lock()
+		w.closed = true
+		close(events)
+		w.mu.Unlock()
+	}()
+
+	return events, nil
+}
+
+// emit sends ev on the channel, skipping it if it is identical to the previously emitted event,
+// so that repeated informer resyncs don't spam the user with the same status line.
+func (w *PodReadinessWatcher) emit(events chan<- PodEvent, evType PodEventType, pod *corev1.Pod, message string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed || evType == w.lastEvent {
+		return
+	}
+	w.lastEvent = evType
+	select {
+	case events <- PodEvent{Type: evType, Pod: pod, Message: message}:
+	default:
+		klog.V(3).Infof("dropping pod event %s for component %s, channel full", evType, w.componentName)
+	}
+}
+
+// WaitForReady starts the watcher and blocks until the pod is running (its Ready condition is not
+// required, since a container's readiness probe can itself depend on code the caller is about to
+// sync in), ctx is cancelled, or timeout elapses, returning the pod on success.
+func (w *PodReadinessWatcher) WaitForReady(ctx context.Context, timeout time.Duration) (*corev1.Pod, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	events, err := w.Start(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for ev := range events {
+		switch ev.Type {
+		case PodEventRunning, PodEventReady:
+			return ev.Pod, nil
+		case PodEventDeleted:
+			return nil, fmt.Errorf("pod for component %s was deleted while waiting for it to become ready", w.componentName)
+		}
+	}
+
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("timed out waiting for pod for component %s to become ready: %w", w.componentName, ctx.Err())
+	}
+	return nil, fmt.Errorf("pod watch for component %s closed before the pod became ready", w.componentName)
+}
+
+// classifyPod derives a PodEventType from a pod's current conditions and container statuses.
+func classifyPod(pod *corev1.Pod) PodEventType {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady && cond.Status == corev1.ConditionTrue {
+			return PodEventReady
+		}
+	}
+
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.State.Waiting == nil {
+			continue
+		}
+		switch cs.State.Waiting.Reason {
+		case "ImagePullBackOff", "ErrImagePull":
+			return PodEventImagePullBackOff
+		case "CrashLoopBackOff":
+			return PodEventCrashLoopBackOff
+		case "ContainerCreating":
+			return PodEventContainerCreating
+		}
+	}
+
+	if pod.Status.Phase == corev1.PodRunning {
+		return PodEventRunning
+	}
+
+	return PodEventPending
+}
@@ -0,0 +1,102 @@
+package kclient
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestClassifyPod(t *testing.T) {
+	tests := []struct {
+		name string
+		pod  *corev1.Pod
+		want PodEventType
+	}{
+		{
+			name: "ready condition true",
+			pod: &corev1.Pod{Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{
+					{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+				},
+			}},
+			want: PodEventReady,
+		},
+		{
+			name: "ready condition false falls through to container status",
+			pod: &corev1.Pod{Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{
+					{Type: corev1.PodReady, Status: corev1.ConditionFalse},
+				},
+			}},
+			want: PodEventPending,
+		},
+		{
+			name: "running phase with a failing readiness probe is Running, not Pending",
+			pod: &corev1.Pod{Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				Conditions: []corev1.PodCondition{
+					{Type: corev1.PodReady, Status: corev1.ConditionFalse},
+				},
+			}},
+			want: PodEventRunning,
+		},
+		{
+			name: "image pull backoff",
+			pod: &corev1.Pod{Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"}}},
+				},
+			}},
+			want: PodEventImagePullBackOff,
+		},
+		{
+			name: "err image pull maps to the same event as image pull backoff",
+			pod: &corev1.Pod{Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ErrImagePull"}}},
+				},
+			}},
+			want: PodEventImagePullBackOff,
+		},
+		{
+			name: "crash loop backoff",
+			pod: &corev1.Pod{Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+				},
+			}},
+			want: PodEventCrashLoopBackOff,
+		},
+		{
+			name: "container creating",
+			pod: &corev1.Pod{Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"}}},
+				},
+			}},
+			want: PodEventContainerCreating,
+		},
+		{
+			name: "unrecognized waiting reason defaults to pending",
+			pod: &corev1.Pod{Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "SomethingElse"}}},
+				},
+			}},
+			want: PodEventPending,
+		},
+		{
+			name: "no conditions or container statuses",
+			pod:  &corev1.Pod{},
+			want: PodEventPending,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyPod(tt.pod); got != tt.want {
+				t.Errorf("classifyPod() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
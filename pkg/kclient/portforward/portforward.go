@@ -0,0 +1,190 @@
+// Package portforward implements a long-lived SPDY port-forward session for a component's pod,
+// so `odo push --forward` can expose a running container back to the developer's workstation
+// without a separate `odo url`/`kubectl port-forward` invocation.
+package portforward
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+	"k8s.io/klog"
+
+	"github.com/openshift/odo/pkg/kclient"
+	"github.com/openshift/odo/pkg/log"
+)
+
+// Binding is a single local->pod:port forward odo has established.
+type Binding struct {
+	LocalPort uint16
+	PodPort   uint16
+}
+
+// Forwarder manages the SPDY port-forward session for a single component. It is reattached to a
+// new pod whenever the caller observes a pod name change (e.g. via a kclient.PodReadinessWatcher
+// stream), so the developer's session survives a redeploy.
+type Forwarder struct {
+	client        kclient.Client
+	componentName string
+
+	mu      sync.Mutex
+	stopCh  chan struct{}
+	errCh   chan error
+	podName string
+}
+
+// NewForwarder creates a Forwarder for componentName's pod(s) in client's namespace.
+func NewForwarder(client kclient.Client, componentName string) *Forwarder {
+	return &Forwarder{client: client, componentName: componentName}
+}
+
+// Start tears down any existing forward and opens a new one against podName, binding
+// 127.0.0.1:<port> to <podName>:<port> for every containerPort declared across containers. It
+// blocks until the forwards are ready and logs the resulting local -> pod:port bindings.
+func (f *Forwarder) Start(podName string, containers []corev1.Container) ([]Binding, error) {
+	f.Stop()
+
+	ports := containerPorts(containers)
+	if len(ports) == 0 {
+		return nil, nil
+	}
+
+	transport, upgrader, err := spdy.RoundTripperFor(f.client.KubeConfig)
+	if err != nil {
+		return nil, fmt.Errorf("unable to build SPDY round tripper for port-forward: %w", err)
+	}
+
+	req := f.client.KubeClient.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(f.client.Namespace).
+		Name(podName).
+		SubResource("portforward")
+
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", req.URL())
+
+	var specs []string
+	var bindings []Binding
+	for _, port := range ports {
+		specs = append(specs, fmt.Sprintf("%d:%d", port, port))
+		bindings = append(bindings, Binding{LocalPort: port, PodPort: port})
+	}
+
+	stopCh := make(chan struct{})
+	readyCh := make(chan struct{})
+
+	fw, err := portforward.New(dialer, specs, stopCh, readyCh, ioutil.Discard, ioutil.Discard)
+	if err != nil {
+		close(stopCh)
+		return nil, fmt.Errorf("unable to set up port-forward to pod %s: %w", podName, err)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- fw.ForwardPorts()
+	}()
+
+	select {
+	case <-readyCh:
+	case err := <-errCh:
+		return nil, fmt.Errorf("port-forward to pod %s exited before becoming ready: %w", podName, err)
+	}
+
+	f.mu.Lock()
+	f.stopCh = stopCh
+	f.errCh = errCh
+	f.podName = podName
+	f.mu.Unlock()
+
+	log.Info("\nForwarding ports")
+	for _, b := range bindings {
+		log.Infof("  127.0.0.1:%d -> %s:%d", b.LocalPort, podName, b.PodPort)
+	}
+
+	return bindings, nil
+}
+
+// Stop tears down the active forward, if any. It is safe to call when no forward is active.
+func (f *Forwarder) Stop() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.stopCh != nil {
+		close(f.stopCh)
+		f.stopCh = nil
+		f.errCh = nil
+		f.podName = ""
+	}
+}
+
+// WatchAndForward starts a forward against initialPodName/initialContainers and then reattaches
+// automatically, using the new pod's own containers, whenever podEvents reports a Ready pod with
+// a different name. It also watches for the active forward dying on its own (e.g. a dropped
+// connection to the same pod) and warns the user, since that isn't signalled by podEvents.
+func (f *Forwarder) WatchAndForward(ctx context.Context, initialPodName string, initialContainers []corev1.Container, podEvents <-chan kclient.PodEvent) error {
+	if _, err := f.Start(initialPodName, initialContainers); err != nil {
+		return err
+	}
+
+	go func() {
+		defer f.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case err, ok := <-f.currentErrCh():
+				if !ok {
+					continue
+				}
+				if err != nil {
+					log.Warningf("port-forward to pod %s exited unexpectedly: %v", f.currentPodName(), err)
+				}
+			case ev, ok := <-podEvents:
+				if !ok {
+					return
+				}
+				if ev.Type != kclient.PodEventReady || ev.Pod == nil || ev.Pod.Name == f.currentPodName() {
+					continue
+				}
+				klog.V(2).Infof("pod for component %s changed to %s, reattaching port-forward", f.componentName, ev.Pod.Name)
+				if _, err := f.Start(ev.Pod.Name, ev.Pod.Spec.Containers); err != nil {
+					log.Warningf("failed to reattach port-forward to pod %s: %v", ev.Pod.Name, err)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (f *Forwarder) currentPodName() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.podName
+}
+
+func (f *Forwarder) currentErrCh() chan error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.errCh
+}
+
+// containerPorts collects the distinct container ports declared across containers.
+func containerPorts(containers []corev1.Container) []uint16 {
+	seen := map[uint16]bool{}
+	var ports []uint16
+	for _, c := range containers {
+		for _, p := range c.Ports {
+			port := uint16(p.ContainerPort)
+			if seen[port] {
+				continue
+			}
+			seen[port] = true
+			ports = append(ports, port)
+		}
+	}
+	return ports
+}
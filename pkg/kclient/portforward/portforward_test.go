@@ -0,0 +1,56 @@
+package portforward
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestContainerPorts(t *testing.T) {
+	tests := []struct {
+		name       string
+		containers []corev1.Container
+		want       []uint16
+	}{
+		{name: "no containers", containers: nil, want: nil},
+		{
+			name: "no ports declared",
+			containers: []corev1.Container{
+				{Name: "app"},
+			},
+			want: nil,
+		},
+		{
+			name: "single container, single port",
+			containers: []corev1.Container{
+				{Name: "app", Ports: []corev1.ContainerPort{{ContainerPort: 8080}}},
+			},
+			want: []uint16{8080},
+		},
+		{
+			name: "multiple containers, distinct ports preserve first-seen order",
+			containers: []corev1.Container{
+				{Name: "app", Ports: []corev1.ContainerPort{{ContainerPort: 8080}, {ContainerPort: 9000}}},
+				{Name: "sidecar", Ports: []corev1.ContainerPort{{ContainerPort: 9090}}},
+			},
+			want: []uint16{8080, 9000, 9090},
+		},
+		{
+			name: "duplicate ports across containers are deduplicated",
+			containers: []corev1.Container{
+				{Name: "app", Ports: []corev1.ContainerPort{{ContainerPort: 8080}}},
+				{Name: "sidecar", Ports: []corev1.ContainerPort{{ContainerPort: 8080}}},
+			},
+			want: []uint16{8080},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := containerPorts(tt.containers); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("containerPorts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
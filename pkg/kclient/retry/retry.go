@@ -0,0 +1,108 @@
+// Package retry provides a small exponential-backoff retry helper for kclient operations that
+// can fail transiently on shared clusters (conflicts, throttling, brief API unavailability).
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+	"time"
+
+	"github.com/jpillora/backoff"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog"
+)
+
+// DefaultBackoff is the backoff schedule used by Do when no custom backoff.Backoff is supplied.
+func DefaultBackoff() *backoff.Backoff {
+	return &backoff.Backoff{
+		Min:    500 * time.Millisecond,
+		Max:    30 * time.Second,
+		Factor: 2,
+		Jitter: true,
+	}
+}
+
+// RetryExhaustedError is returned once an operation has failed with a retryable error on all
+// MaxAttempts attempts.
+type RetryExhaustedError struct {
+	Operation string
+	Attempts  int
+	Err       error
+}
+
+func (e *RetryExhaustedError) Error() string {
+	return fmt.Sprintf("operation %q did not succeed after %d attempt(s): %v", e.Operation, e.Attempts, e.Err)
+}
+
+func (e *RetryExhaustedError) Unwrap() error {
+	return e.Err
+}
+
+// MaxAttempts bounds how many times Do will invoke the operation, regardless of the backoff
+// schedule's Max duration, so a persistently-throttled cluster can't hang odo push forever.
+const MaxAttempts = 10
+
+// Do invokes op, retrying according to b whenever the returned error is classified as transient
+// by IsRetryable. operation is a short human-readable name used in log lines and in the returned
+// RetryExhaustedError.
+func Do(operation string, b *backoff.Backoff, op func() error) error {
+	if b == nil {
+		b = DefaultBackoff()
+	}
+	b.Reset()
+
+	var lastErr error
+	for attempt := 1; attempt <= MaxAttempts; attempt++ {
+		lastErr = op()
+		if lastErr == nil {
+			return nil
+		}
+
+		if !IsRetryable(lastErr) {
+			return lastErr
+		}
+		if attempt == MaxAttempts {
+			break
+		}
+
+		d := b.Duration()
+		klog.V(3).Infof("operation %q failed on attempt %d/%d, retrying in %s: %v", operation, attempt, MaxAttempts, d, lastErr)
+		time.Sleep(d)
+	}
+
+	return &RetryExhaustedError{Operation: operation, Attempts: MaxAttempts, Err: lastErr}
+}
+
+// DoWithConflictRefresh behaves like Do, except that when the operation fails with a conflict
+// (the object was updated since it was last read), it calls refresh before retrying so the
+// caller can re-GET the object and re-apply its desired spec on top of the latest
+// resourceVersion. refresh is only invoked for conflicts; other transient errors retry op as-is.
+func DoWithConflictRefresh(operation string, b *backoff.Backoff, op func() error, refresh func() error) error {
+	return Do(operation, b, func() error {
+		err := op()
+		if kerrors.IsConflict(err) {
+			if refreshErr := refresh(); refreshErr != nil {
+				return refreshErr
+			}
+		}
+		return err
+	})
+}
+
+// IsRetryable classifies whether err represents a transient condition worth retrying. It
+// surrenders on errors that retrying cannot fix, such as IsNotFound, IsForbidden and IsInvalid.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	switch {
+	case kerrors.IsNotFound(err), kerrors.IsForbidden(err), kerrors.IsInvalid(err):
+		return false
+	case kerrors.IsConflict(err), kerrors.IsServerTimeout(err), kerrors.IsTooManyRequests(err), kerrors.IsInternalError(err):
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNRESET)
+}
@@ -0,0 +1,54 @@
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestIsRetryable(t *testing.T) {
+	gr := schema.GroupResource{Resource: "deployments"}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil error", err: nil, want: false},
+		{name: "not found", err: kerrors.NewNotFound(gr, "foo"), want: false},
+		{name: "forbidden", err: kerrors.NewForbidden(gr, "foo", nil), want: false},
+		{name: "invalid", err: kerrors.NewInvalid(schema.GroupKind{Kind: "Deployment"}, "foo", nil), want: false},
+		{name: "conflict", err: kerrors.NewConflict(gr, "foo", nil), want: true},
+		{name: "server timeout", err: kerrors.NewServerTimeout(gr, "update", 1), want: true},
+		{name: "too many requests", err: kerrors.NewTooManyRequests("slow down", 1), want: true},
+		{name: "internal error", err: kerrors.NewInternalError(fmt.Errorf("boom")), want: true},
+		{name: "connection reset", err: fmt.Errorf("dial: %w", syscall.ECONNRESET), want: true},
+		{name: "unrelated error", err: errors.New("something else"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Errorf("IsRetryable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryExhaustedError(t *testing.T) {
+	cause := errors.New("still failing")
+	err := &RetryExhaustedError{Operation: "update deployment", Attempts: MaxAttempts, Err: cause}
+
+	wantMsg := fmt.Sprintf("operation %q did not succeed after %d attempt(s): %v", "update deployment", MaxAttempts, cause)
+	if got := err.Error(); got != wantMsg {
+		t.Errorf("Error() = %q, want %q", got, wantMsg)
+	}
+
+	if !errors.Is(err, cause) {
+		t.Errorf("errors.Is(err, cause) = false, want true via Unwrap()")
+	}
+}
@@ -0,0 +1,64 @@
+package component
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/odo/pkg/devfile/adapters/common"
+	"github.com/openshift/odo/pkg/envinfo"
+)
+
+// PushRecommendedCommandName is the recommended push command name.
+const PushRecommendedCommandName = "push"
+
+// pusher is satisfied by the devfile kubernetes component Adapter.
+type pusher interface {
+	Push(parameters common.PushParameters) error
+}
+
+// PushOptions holds the push command's flag and adapter state. EnvSpecificInfo and Adapter are
+// populated by this options type's Complete() (alongside the rest of the existing push context
+// wiring); pushFlags holds the values AddPushFlags bound directly to the command's flags.
+type PushOptions struct {
+	pushFlags *PushFlagVars
+
+	EnvSpecificInfo envinfo.EnvSpecificInfo
+	DevfileBuildCmd string
+	DevfileRunCmd   string
+	DevfileDebugCmd string
+	DebugPort       int
+	Debug           bool
+	Show            bool
+
+	Adapter pusher
+}
+
+// NewCmdPush implements the push odo command, registering the --registry-secret, --forward and
+// --forward-keep-alive flags alongside the command's existing flags.
+func NewCmdPush(name, fullName string) *cobra.Command {
+	o := &PushOptions{}
+	pushCmd := &cobra.Command{
+		Use:   name,
+		Short: "Push source code to a devfile component",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.Run()
+		},
+	}
+	o.pushFlags = AddPushFlags(pushCmd)
+	return pushCmd
+}
+
+// Run assembles this invocation's common.PushParameters from the completed option state and the
+// registered push flags, and hands it to the adapter.
+func (o *PushOptions) Run() error {
+	parameters := common.PushParameters{
+		EnvSpecificInfo: o.EnvSpecificInfo,
+		DevfileBuildCmd: o.DevfileBuildCmd,
+		DevfileRunCmd:   o.DevfileRunCmd,
+		DevfileDebugCmd: o.DevfileDebugCmd,
+		DebugPort:       o.DebugPort,
+		Debug:           o.Debug,
+		Show:            o.Show,
+	}
+	o.pushFlags.ApplyTo(&parameters)
+	return o.Adapter.Push(parameters)
+}
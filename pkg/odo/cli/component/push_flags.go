@@ -0,0 +1,45 @@
+package component
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/odo/pkg/devfile/adapters/common"
+)
+
+const (
+	// registrySecretFlagName lets the user point `odo push` at an existing dockerconfigjson
+	// Secret instead of having odo discover pull credentials from the local docker config.
+	registrySecretFlagName = "registry-secret"
+	// forwardFlagName starts an integrated port-forward session once the push completes.
+	forwardFlagName = "forward"
+	// forwardKeepAliveFlagName leaves the component deployed after a --forward session ends.
+	forwardKeepAliveFlagName = "forward-keep-alive"
+)
+
+// PushFlagVars holds the cobra-bound values for the push-specific flags registered by
+// AddPushFlags, ready to be copied onto a common.PushParameters via ApplyTo.
+type PushFlagVars struct {
+	RegistrySecret   string
+	Forward          bool
+	ForwardKeepAlive bool
+}
+
+// AddPushFlags registers the --registry-secret, --forward and --forward-keep-alive flags on
+// pushCmd and returns the vars they're bound to.
+func AddPushFlags(pushCmd *cobra.Command) *PushFlagVars {
+	vars := &PushFlagVars{}
+	pushCmd.Flags().StringVar(&vars.RegistrySecret, registrySecretFlagName, "",
+		"Name of an existing image pull secret to use for private registry images, instead of discovering credentials from the local docker config")
+	pushCmd.Flags().BoolVar(&vars.Forward, forwardFlagName, false,
+		"Start a port-forward session to the component after a successful push")
+	pushCmd.Flags().BoolVar(&vars.ForwardKeepAlive, forwardKeepAliveFlagName, true,
+		"Leave the component deployed when a --forward session ends")
+	return vars
+}
+
+// ApplyTo copies the push flag values onto parameters.
+func (v *PushFlagVars) ApplyTo(parameters *common.PushParameters) {
+	parameters.RegistrySecret = v.RegistrySecret
+	parameters.Forward = v.Forward
+	parameters.ForwardKeepAlive = v.ForwardKeepAlive
+}